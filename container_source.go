@@ -0,0 +1,342 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logtail
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vogo/gstop"
+	"github.com/vogo/logger"
+)
+
+// ContainerRuntime selects which daemon socket a ContainerSource attaches to.
+type ContainerRuntime string
+
+const (
+	ContainerRuntimeDocker     ContainerRuntime = "docker"
+	ContainerRuntimeContainerd ContainerRuntime = "containerd"
+)
+
+const (
+	defaultDockerSocket      = "/var/run/docker.sock"
+	defaultContainerdSocket  = "/run/containerd/containerd.sock"
+	containerReattachWait    = 5 * time.Second
+	dockerStreamHeaderLength = 8
+)
+
+var ErrContainerNotFound = errors.New("container not found")
+
+// ContainerSourceConfig describes a container (or label selector) to tail.
+// Router is the id of the already-running Server whose routers the de-muxed
+// stdout/stderr workers feed.
+type ContainerSourceConfig struct {
+	Router    string           `json:"router"`
+	Runtime   ContainerRuntime `json:"runtime"`
+	Container string           `json:"container"`
+	Label     string           `json:"label"`
+	Since     time.Duration    `json:"since"`
+	Follow    bool             `json:"follow"`
+}
+
+// ContainerSource tails `docker logs -f`-equivalent output without a
+// subprocess: it dials the runtime's control socket directly, de-muxes the
+// stdout/stderr stream header, and feeds each side into its own dynamic
+// worker so the existing filter/transfer pipeline is reused unchanged.
+type ContainerSource struct {
+	mu        sync.Mutex
+	server    *Server
+	config    ContainerSourceConfig
+	stopper   *gstop.Stopper
+	stdout    *worker
+	stderr    *worker
+	container string // resolved container id currently attached, if any
+}
+
+func newContainerSource(s *Server, config ContainerSourceConfig) *ContainerSource {
+	return &ContainerSource{
+		server:  s,
+		config:  config,
+		stopper: gstop.New(),
+	}
+}
+
+// start attaches cs.stdout/cs.stderr through the same path startWorker uses
+// for any other worker - filters first, then start() - so they show up in
+// workerRegistry, publish the usual EventStarted/EventCompleted events, and
+// tear down through worker.shutdown() like every other worker. They are
+// intentionally not appended to server.workers: ContainerSource itself owns
+// their lifecycle. The two workers are created once here and stay in place
+// while run() re-attaches to containers as they exit and reappear; they are
+// not torn down per container, only by stop().
+func (cs *ContainerSource) start() error {
+	cs.stdout = newWorker(cs.server, "", true)
+	cs.stdout.id = fmt.Sprintf("%s-stdout", cs.server.id)
+	cs.stderr = newWorker(cs.server, "", true)
+	cs.stderr.id = fmt.Sprintf("%s-stderr", cs.server.id)
+
+	for _, w := range [...]*worker{cs.stdout, cs.stderr} {
+		for _, r := range cs.server.routers {
+			w.StartRouterFilter(r)
+		}
+
+		w.start()
+	}
+
+	go cs.run()
+
+	return nil
+}
+
+// stop closes cs.stopper, which ends the attach loop in run()/wait(), then
+// tears down the shared stdout/stderr workers through the normal worker
+// shutdown path.
+func (cs *ContainerSource) stop() {
+	cs.stopper.Stop()
+	cs.stdout.shutdown()
+	cs.stderr.shutdown()
+}
+
+func (cs *ContainerSource) run() {
+	for {
+		select {
+		case <-cs.stopper.C:
+			return
+		case <-cs.server.stopper.C:
+			return
+		default:
+		}
+
+		id, err := cs.resolveContainer()
+		if err != nil {
+			logger.Errorf("container source [%s] resolve error: %+v", cs.config.Container, err)
+			cs.wait()
+
+			continue
+		}
+
+		cs.mu.Lock()
+		cs.container = id
+		cs.mu.Unlock()
+
+		if err := cs.attach(id); err != nil {
+			logger.Errorf("container source [%s] attach error: %+v", id, err)
+		}
+
+		// the container stopped or the connection dropped: report it like any
+		// other dynamic worker instead of silently retrying the same id.
+		cs.server.receiveWorkerError(fmt.Errorf("%w: container [%s] log stream ended", ErrWorkerCommandStopped, id))
+
+		if cs.config.Label == "" {
+			return
+		}
+
+		cs.wait()
+	}
+}
+
+func (cs *ContainerSource) wait() {
+	select {
+	case <-cs.stopper.C:
+	case <-cs.server.stopper.C:
+	case <-time.After(containerReattachWait):
+	}
+}
+
+func (cs *ContainerSource) socketPath() string {
+	if cs.config.Runtime == ContainerRuntimeContainerd {
+		return defaultContainerdSocket
+	}
+
+	return defaultDockerSocket
+}
+
+// resolveContainer returns the container id to attach to, either the
+// configured one or, when a label selector is set, the first running
+// container matching it.
+func (cs *ContainerSource) resolveContainer() (string, error) {
+	if cs.config.Label == "" {
+		return cs.config.Container, nil
+	}
+
+	conn, err := net.Dial("unix", cs.socketPath())
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	path := fmt.Sprintf("/containers/json?filters=%s", queryEscapeLabelFilter(cs.config.Label))
+
+	resp, err := dockerGetJSON(conn, path)
+	if err != nil {
+		return "", err
+	}
+
+	id := firstContainerID(resp)
+	if id == "" {
+		return "", fmt.Errorf("%w: label %q", ErrContainerNotFound, cs.config.Label)
+	}
+
+	return id, nil
+}
+
+// attach opens the logs endpoint and demuxes frames until the stream ends.
+func (cs *ContainerSource) attach(id string) error {
+	conn, err := net.Dial("unix", cs.socketPath())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	query := fmt.Sprintf("follow=%t&stdout=1&stderr=1&since=%d", cs.config.Follow, sinceUnix(cs.config.Since))
+	path := fmt.Sprintf("/containers/%s/logs?%s", id, query)
+
+	body, err := dockerOpenLogStream(conn, path)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	return demuxDockerStream(body, cs.stdout, cs.stderr)
+}
+
+// demuxDockerStream splits the docker multiplexed log stream into stdout and
+// stderr frames per https://docs.docker.com/engine/api/v1.41/#tag/Container:
+// each frame is an 8 byte header (stream type, 3 reserved bytes, big-endian
+// uint32 length) followed by that many bytes of payload.
+func demuxDockerStream(r io.Reader, stdout, stderr io.Writer) error {
+	header := make([]byte, dockerStreamHeaderLength)
+
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return err
+		}
+
+		length := binary.BigEndian.Uint32(header[4:8])
+		payload := make([]byte, length)
+
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return err
+		}
+
+		switch header[0] {
+		case 2:
+			_, _ = stderr.Write(payload)
+		default:
+			_, _ = stdout.Write(payload)
+		}
+	}
+}
+
+func sinceUnix(d time.Duration) int64 {
+	if d <= 0 {
+		return 0
+	}
+
+	return time.Now().Add(-d).Unix()
+}
+
+func queryEscapeLabelFilter(label string) string {
+	return fmt.Sprintf("{%%22label%%22:[%%22%s%%22]}", label)
+}
+
+// dockerRequest writes a minimal HTTP/1.1 GET request for path over conn and
+// returns the parsed response. request.Close tells the server not to keep
+// the connection alive, since conn is used for exactly one request.
+func dockerRequest(conn net.Conn, path string) (*http.Response, error) {
+	request, err := http.NewRequest(http.MethodGet, "http://docker"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("host", "docker")
+	request.Close = true
+
+	if err := request.Write(conn); err != nil {
+		return nil, err
+	}
+
+	response, err := http.ReadResponse(bufio.NewReader(conn), request)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		defer response.Body.Close()
+
+		return nil, fmt.Errorf("%w: %s", ErrContainerNotFound, response.Status)
+	}
+
+	return response, nil
+}
+
+// dockerGetJSON issues path and reads the whole JSON response body, honoring
+// the response's chunked/Content-Length framing so it observes EOF even on
+// a connection the daemon would otherwise keep alive.
+func dockerGetJSON(conn net.Conn, path string) ([]byte, error) {
+	response, err := dockerRequest(conn, path)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	return io.ReadAll(response.Body)
+}
+
+// dockerOpenLogStream issues path and returns the response body for the
+// caller to demux. The caller owns closing it.
+func dockerOpenLogStream(conn net.Conn, path string) (io.ReadCloser, error) {
+	response, err := dockerRequest(conn, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Body, nil
+}
+
+func firstContainerID(body []byte) string {
+	const idKey = `"Id":"`
+
+	text := string(body)
+
+	idx := strings.Index(text, idKey)
+	if idx < 0 {
+		return ""
+	}
+
+	start := idx + len(idKey)
+	end := strings.IndexByte(text[start:], '"')
+
+	if end < 0 {
+		return ""
+	}
+
+	return text[start : start+end]
+}
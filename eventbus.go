@@ -0,0 +1,114 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logtail
+
+import "sync"
+
+// EventStatus mirrors buildkit's jsonmessage progress stream status values.
+type EventStatus string
+
+const (
+	EventStarted   EventStatus = "started"
+	EventRunning   EventStatus = "running"
+	EventCompleted EventStatus = "completed"
+	EventError     EventStatus = "error"
+)
+
+// defaultEventBufferSize is the per-subscriber ring buffer capacity; once
+// full, Publish drops the event rather than blocking the publisher.
+const defaultEventBufferSize = 256
+
+// EventProgress carries the optional counters a status update may report,
+// e.g. bytes written, files rotated, or filter matches. Matches is
+// published by worker.publishRouterMatch each time a worker dispatches a
+// chunk to one of its router filters.
+type EventProgress struct {
+	Bytes   int64 `json:"bytes,omitempty"`
+	Files   int64 `json:"files,omitempty"`
+	Matches int64 `json:"matches,omitempty"`
+}
+
+// Event is the envelope published on the EventBus whenever a worker,
+// transfer or router changes state.
+type Event struct {
+	ID        string         `json:"id"`
+	ParentID  string         `json:"parent_id,omitempty"`
+	Status    EventStatus    `json:"status"`
+	Progress  *EventProgress `json:"progress,omitempty"`
+	Timestamp int64          `json:"timestamp"`
+}
+
+// EventBus fans out Events to any number of subscribers without letting a
+// slow reader back-pressure the publisher: Publish never blocks, and each
+// subscriber only ever loses events off its own ring buffer.
+type EventBus struct {
+	mu          sync.Mutex
+	subscribers map[int]chan Event
+	nextID      int
+}
+
+func newEventBus() *EventBus {
+	return &EventBus{
+		subscribers: make(map[int]chan Event),
+	}
+}
+
+var defaultEventBus = newEventBus()
+
+// Events returns the process-wide EventBus that workers and transfers
+// publish to.
+func Events() *EventBus {
+	return defaultEventBus
+}
+
+// Publish sends event to every current subscriber. It never blocks: a
+// subscriber whose ring buffer is full simply misses the event.
+func (b *EventBus) Publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe registers a new ring buffer and returns it along with a cancel
+// func the caller must invoke once it stops reading.
+func (b *EventBus) Subscribe() (<-chan Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+
+	ch := make(chan Event, defaultEventBufferSize)
+	b.subscribers[id] = ch
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if sub, ok := b.subscribers[id]; ok {
+			delete(b.subscribers, id)
+			close(sub)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+//go:build windows
+// +build windows
+
+package logtail
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/vogo/logger"
+	"github.com/vogo/vogo/vio/vioutil"
+)
+
+type FileTransfer struct {
+	router    *Router
+	dir       string
+	prefix    string
+	name      string
+	liveName  atomic.Value // string; current live (pre-rotation) filename, read by Stats()
+	buffer    chan [][]byte
+	writeSize int64 // read concurrently by Stats(); only mutated via atomic ops
+	mapping   mmapHandle
+	file      *os.File
+	dropped   int64 // count of Trans calls discarded because buffer was full
+}
+
+func NewFileTransfer(dir string) Transfer {
+	return &FileTransfer{
+		dir: dir,
+	}
+}
+
+func (ft *FileTransfer) resetFile() error {
+	var err error
+
+	if !vioutil.ExistDir(ft.dir) {
+		err = os.Mkdir(ft.dir, os.ModePerm)
+		if err != nil {
+			return err
+		}
+	}
+
+	ft.name = filepath.Join(ft.dir, ft.prefix+"-"+time.Now().Format(`20060102150405`)+".log")
+	ft.liveName.Store(ft.name)
+	ft.file, err = os.Create(ft.name)
+
+	if err != nil {
+		return err
+	}
+
+	err = ft.file.Truncate(TransferFileSize)
+	if err != nil {
+		return err
+	}
+
+	ft.mapping, err = defaultMmapFile.Map(ft.file, TransferFileSize)
+	if err != nil {
+		return err
+	}
+
+	atomic.StoreInt64(&ft.writeSize, 0)
+
+	return nil
+}
+
+func (ft *FileTransfer) submitFile() error {
+	defer func() {
+		ft.mapping = mmapHandle{}
+		ft.file = nil
+		ft.name = ""
+		ft.liveName.Store("")
+		atomic.StoreInt64(&ft.writeSize, 0)
+	}()
+
+	if ft.file != nil {
+		writeSize := atomic.LoadInt64(&ft.writeSize)
+
+		logger.Infof("submit file %s", ft.name)
+
+		_ = defaultMmapFile.Unmap(ft.mapping)
+		_ = ft.file.Truncate(writeSize)
+
+		if writeSize == 0 {
+			ft.file.Close()
+
+			return os.Remove(ft.name)
+		}
+
+		Events().Publish(Event{
+			ID:        ft.name,
+			ParentID:  ft.router.id,
+			Status:    EventCompleted,
+			Progress:  &EventProgress{Bytes: writeSize, Files: 1},
+			Timestamp: time.Now().UnixNano(),
+		})
+
+		return ft.file.Close()
+	}
+
+	return nil
+}
+
+func (ft *FileTransfer) start(r *Router) error {
+	ft.prefix = r.name
+	ft.router = r
+
+	if err := ft.resetFile(); err != nil {
+		return err
+	}
+
+	go func() {
+		ft.buffer = make(chan [][]byte, DefaultChannelBufferSize)
+		transferRegistry.Store(ft.prefix, ft)
+
+		defer func() {
+			_ = ft.submitFile()
+			close(ft.buffer)
+			transferRegistry.Delete(ft.prefix)
+		}()
+
+		for {
+			select {
+			case <-ft.router.close:
+				return
+			case data := <-ft.buffer:
+				ft.write(data)
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (ft *FileTransfer) Trans(serverID string, data ...[]byte) error {
+	defer func() {
+		_ = recover()
+	}()
+
+	select {
+	case <-ft.router.close:
+		return nil
+	case ft.buffer <- data:
+	default:
+		atomic.AddInt64(&ft.dropped, 1)
+	}
+
+	return nil
+}
+
+// Stats reports disk usage and backpressure for this transfer: in-flight
+// buffer depth, rotated file count/bytes on disk, and how many Trans calls
+// were discarded by the default: branch above. writeSize and the live
+// filename are read via atomic load since the transfer goroutine mutates
+// them concurrently in write()/resetFile()/submitFile().
+func (ft *FileTransfer) Stats() FileTransferStats {
+	liveName, _ := ft.liveName.Load().(string)
+	files, bytes := diskUsage(ft.dir, ft.prefix, liveName)
+
+	return FileTransferStats{
+		Name:         ft.prefix,
+		WriteSize:    atomic.LoadInt64(&ft.writeSize),
+		RotatedFiles: files,
+		RotatedBytes: bytes,
+		BufferDepth:  len(ft.buffer),
+		BufferCap:    cap(ft.buffer),
+		Dropped:      atomic.LoadInt64(&ft.dropped),
+	}
+}
+
+func (ft *FileTransfer) write(data [][]byte) {
+	if ft.file == nil {
+		if err := ft.resetFile(); err != nil {
+			logger.Errorf("reset file error: %v", err)
+
+			return
+		}
+	}
+
+	length := int64(0)
+	for _, d := range data {
+		length += int64(len(d)) + 1
+	}
+
+	writeSize := atomic.LoadInt64(&ft.writeSize)
+
+	if TransferFileSize-writeSize < length {
+		if err := ft.submitFile(); err != nil {
+			logger.Errorf("submit file error: %v", err)
+		}
+
+		if err := ft.resetFile(); err != nil {
+			logger.Errorf("reset file error: %v", err)
+
+			return
+		}
+
+		writeSize = 0
+	}
+
+	for _, b := range data {
+		copy(ft.mapping.bytes[writeSize:], b)
+		writeSize += int64(len(b))
+		ft.mapping.bytes[writeSize] = '\n'
+		writeSize++
+	}
+
+	// single store after the copy loop: writeSize only needs to be visible
+	// to Stats() once the whole batch has landed in the mapping.
+	atomic.StoreInt64(&ft.writeSize, writeSize)
+}
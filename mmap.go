@@ -0,0 +1,19 @@
+package logtail
+
+import "os"
+
+// mmapHandle is the live memory mapping FileTransfer writes into, plus
+// whatever platform-specific handle Unmap needs to release it again.
+type mmapHandle struct {
+	bytes []byte
+	sys   uintptr
+}
+
+// mmapFile is the OS-specific surface FileTransfer relies on to back a
+// rotated log file with a writable memory mapping, extracted so future
+// backends (io_uring on linux, MAP_ANON-backed darwin, ...) can be swapped
+// in without touching the rotate/truncate/write logic.
+type mmapFile interface {
+	Map(f *os.File, size int) (mmapHandle, error)
+	Unmap(h mmapHandle) error
+}
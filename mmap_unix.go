@@ -0,0 +1,26 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package logtail
+
+import (
+	"os"
+	"syscall"
+)
+
+type unixMmapFile struct{}
+
+var defaultMmapFile mmapFile = unixMmapFile{}
+
+func (unixMmapFile) Map(f *os.File, size int) (mmapHandle, error) {
+	b, err := syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		return mmapHandle{}, err
+	}
+
+	return mmapHandle{bytes: b}, nil
+}
+
+func (unixMmapFile) Unmap(h mmapHandle) error {
+	return syscall.Munmap(h.bytes)
+}
@@ -0,0 +1,41 @@
+//go:build windows
+// +build windows
+
+package logtail
+
+import (
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+type windowsMmapFile struct{}
+
+var defaultMmapFile mmapFile = windowsMmapFile{}
+
+func (windowsMmapFile) Map(f *os.File, size int) (mmapHandle, error) {
+	mapping, err := windows.CreateFileMapping(windows.Handle(f.Fd()), nil, windows.PAGE_READWRITE, 0, uint32(size), nil)
+	if err != nil {
+		return mmapHandle{}, err
+	}
+
+	addr, err := windows.MapViewOfFile(mapping, windows.FILE_MAP_WRITE, 0, 0, uintptr(size))
+	if err != nil {
+		_ = windows.CloseHandle(mapping)
+
+		return mmapHandle{}, err
+	}
+
+	b := unsafe.Slice((*byte)(unsafe.Pointer(addr)), size)
+
+	return mmapHandle{bytes: b, sys: uintptr(mapping)}, nil
+}
+
+func (windowsMmapFile) Unmap(h mmapHandle) error {
+	if err := windows.UnmapViewOfFile(uintptr(unsafe.Pointer(&h.bytes[0]))); err != nil {
+		return err
+	}
+
+	return windows.CloseHandle(windows.Handle(h.sys))
+}
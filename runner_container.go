@@ -0,0 +1,79 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logtail
+
+import (
+	"fmt"
+	"sync"
+)
+
+// containerSources tracks the running ContainerSources keyed by
+// "<router>/<container-or-label>" so they can be torn down by name.
+var containerSources sync.Map // key -> *ContainerSource
+
+func containerSourceKey(router, name string) string {
+	return router + "/" + name
+}
+
+// AddContainerSource attaches a container's stdout/stderr log stream to the
+// routers of the server whose id is config.Router.
+func (runner *Runner) AddContainerSource(config *ContainerSourceConfig) error {
+	if config.Container == "" && config.Label == "" {
+		return fmt.Errorf("%w: container id or label is required", ErrContainerNotFound)
+	}
+
+	server, ok := serverByID(config.Router)
+	if !ok {
+		return fmt.Errorf("%w: router %q", ErrContainerNotFound, config.Router)
+	}
+
+	name := config.Container
+	if name == "" {
+		name = config.Label
+	}
+
+	key := containerSourceKey(config.Router, name)
+	if _, exists := containerSources.Load(key); exists {
+		return fmt.Errorf("container source %q already exists", key)
+	}
+
+	cs := newContainerSource(server, *config)
+
+	if err := cs.start(); err != nil {
+		return err
+	}
+
+	containerSources.Store(key, cs)
+
+	return nil
+}
+
+// RemoveContainerSource stops the container source previously added for
+// router/name.
+func (runner *Runner) RemoveContainerSource(router, name string) error {
+	key := containerSourceKey(router, name)
+
+	v, ok := containerSources.LoadAndDelete(key)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrContainerNotFound, key)
+	}
+
+	v.(*ContainerSource).stop()
+
+	return nil
+}
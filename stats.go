@@ -0,0 +1,121 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logtail
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// transferRegistry tracks the live FileTransfers by router name, populated
+// by the platform-specific FileTransfer implementations.
+var transferRegistry sync.Map // name -> *FileTransfer
+
+// RouterStats identifies a live router for the /stats "routers" group.
+type RouterStats struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// FileTransferStats is a point-in-time snapshot of one FileTransfer,
+// reported over /stats so an operator can see disk usage and backpressure
+// without a separate exporter.
+type FileTransferStats struct {
+	Name         string `json:"name"`
+	WriteSize    int64  `json:"write_size"`
+	RotatedFiles int    `json:"rotated_files"`
+	RotatedBytes int64  `json:"rotated_bytes"`
+	BufferDepth  int    `json:"buffer_depth"`
+	BufferCap    int    `json:"buffer_cap"`
+	Dropped      int64  `json:"dropped"`
+}
+
+// StatsGroup follows the shape of `docker system df --verbose`: a named
+// group of item rows plus the total count, so logtail can be scraped by an
+// operator without a separate exporter.
+type StatsGroup struct {
+	Type  string      `json:"type"`
+	Total int         `json:"total"`
+	Items interface{} `json:"items"`
+}
+
+// CollectStats snapshots every live router, worker and FileTransfer; other
+// transfer types are not included in the "transfers" group.
+func CollectStats() []StatsGroup {
+	routers := make([]RouterStats, 0)
+	routerRegistry.Range(func(k, v interface{}) bool {
+		routers = append(routers, RouterStats{ID: k.(string), Name: v.(string)})
+
+		return true
+	})
+
+	workers := make([]WorkerStats, 0)
+	workerRegistry.Range(func(k, v interface{}) bool {
+		workers = append(workers, v.(*worker).Stats())
+
+		return true
+	})
+
+	transfers := make([]FileTransferStats, 0)
+	transferRegistry.Range(func(k, v interface{}) bool {
+		transfers = append(transfers, v.(*FileTransfer).Stats())
+
+		return true
+	})
+
+	return []StatsGroup{
+		{Type: "routers", Total: len(routers), Items: routers},
+		{Type: "workers", Total: len(workers), Items: workers},
+		{Type: "transfers", Total: len(transfers), Items: transfers},
+	}
+}
+
+// diskUsage counts the rotated log files FileTransfer has left in dir and
+// their total size. liveName, if set, is the file currently being written
+// to (truncated up-front to TransferFileSize by resetFile) and is excluded
+// so it doesn't inflate the rotated count/bytes before it is even rotated.
+func diskUsage(dir, prefix, liveName string) (count int, size int64) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, 0
+	}
+
+	liveBase := filepath.Base(liveName)
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), prefix+"-") {
+			continue
+		}
+
+		if liveName != "" && entry.Name() == liveBase {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		count++
+		size += info.Size()
+	}
+
+	return count, size
+}
@@ -0,0 +1,57 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logtail
+
+import (
+	"fmt"
+
+	"github.com/vogo/logtail/transfer"
+)
+
+// TransferConfig describes a transfer to create via AddTransfer, or tear
+// down via StopTransfer (by Name). Type selects the registered
+// transfer.Types implementation; the remaining fields are
+// implementation-specific and ignored by transfers that don't use them.
+type TransferConfig struct {
+	Name     string            `json:"name"`
+	Type     string            `json:"type"`
+	Dir      string            `json:"dir,omitempty"`
+	Priority int               `json:"priority,omitempty"`
+	Fields   map[string]string `json:"fields,omitempty"`
+}
+
+type transferConstructor func(*TransferConfig) Transfer
+
+var transferConstructors = map[string]transferConstructor{}
+
+// RegisterTransferType makes a transfer buildable from a TransferConfig by
+// name, and lists it in transfer.Types so listTransferTypes reports it.
+func RegisterTransferType(name, description string, constructor transferConstructor) {
+	transferConstructors[name] = constructor
+	transfer.Types[name] = description
+}
+
+// NewTransferFromConfig builds the Transfer registered for config.Type.
+func NewTransferFromConfig(config *TransferConfig) (Transfer, error) {
+	constructor, ok := transferConstructors[config.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown transfer type %q", config.Type)
+	}
+
+	return constructor(config), nil
+}
@@ -1,3 +1,4 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
 // +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
 
 package logtail
@@ -5,7 +6,7 @@ package logtail
 import (
 	"os"
 	"path/filepath"
-	"syscall"
+	"sync/atomic"
 	"time"
 
 	"github.com/vogo/logger"
@@ -13,14 +14,16 @@ import (
 )
 
 type FileTransfer struct {
-	router       *Router
-	dir          string
-	prefix       string
-	name         string
-	buffer       chan [][]byte
-	writeSize    int
-	memoryBuffer []byte
-	file         *os.File
+	router    *Router
+	dir       string
+	prefix    string
+	name      string
+	liveName  atomic.Value // string; current live (pre-rotation) filename, read by Stats()
+	buffer    chan [][]byte
+	writeSize int64 // read concurrently by Stats(); only mutated via atomic ops
+	mapping   mmapHandle
+	file      *os.File
+	dropped   int64 // count of Trans calls discarded because buffer was full
 }
 
 func NewFileTransfer(dir string) Transfer {
@@ -40,6 +43,7 @@ func (ft *FileTransfer) resetFile() error {
 	}
 
 	ft.name = filepath.Join(ft.dir, ft.prefix+"-"+time.Now().Format(`20060102150405`)+".log")
+	ft.liveName.Store(ft.name)
 	ft.file, err = os.Create(ft.name)
 
 	if err != nil {
@@ -51,36 +55,47 @@ func (ft *FileTransfer) resetFile() error {
 		return err
 	}
 
-	ft.memoryBuffer, err = syscall.Mmap(int(ft.file.Fd()), 0, TransferFileSize, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	ft.mapping, err = defaultMmapFile.Map(ft.file, TransferFileSize)
 	if err != nil {
 		return err
 	}
 
-	ft.writeSize = 0
+	atomic.StoreInt64(&ft.writeSize, 0)
 
 	return nil
 }
 
 func (ft *FileTransfer) submitFile() error {
 	defer func() {
-		ft.memoryBuffer = nil
+		ft.mapping = mmapHandle{}
 		ft.file = nil
 		ft.name = ""
-		ft.writeSize = 0
+		ft.liveName.Store("")
+		atomic.StoreInt64(&ft.writeSize, 0)
 	}()
 
 	if ft.file != nil {
+		writeSize := atomic.LoadInt64(&ft.writeSize)
+
 		logger.Infof("submit file %s", ft.name)
 
-		_ = syscall.Munmap(ft.memoryBuffer)
-		_ = ft.file.Truncate(int64(ft.writeSize))
+		_ = defaultMmapFile.Unmap(ft.mapping)
+		_ = ft.file.Truncate(writeSize)
 
-		if ft.writeSize == 0 {
+		if writeSize == 0 {
 			ft.file.Close()
 
 			return os.Remove(ft.name)
 		}
 
+		Events().Publish(Event{
+			ID:        ft.name,
+			ParentID:  ft.router.id,
+			Status:    EventCompleted,
+			Progress:  &EventProgress{Bytes: writeSize, Files: 1},
+			Timestamp: time.Now().UnixNano(),
+		})
+
 		return ft.file.Close()
 	}
 
@@ -97,10 +112,12 @@ func (ft *FileTransfer) start(r *Router) error {
 
 	go func() {
 		ft.buffer = make(chan [][]byte, DefaultChannelBufferSize)
+		transferRegistry.Store(ft.prefix, ft)
 
 		defer func() {
 			_ = ft.submitFile()
 			close(ft.buffer)
+			transferRegistry.Delete(ft.prefix)
 		}()
 
 		for {
@@ -126,11 +143,32 @@ func (ft *FileTransfer) Trans(serverID string, data ...[]byte) error {
 		return nil
 	case ft.buffer <- data:
 	default:
+		atomic.AddInt64(&ft.dropped, 1)
 	}
 
 	return nil
 }
 
+// Stats reports disk usage and backpressure for this transfer: in-flight
+// buffer depth, rotated file count/bytes on disk, and how many Trans calls
+// were discarded by the default: branch above. writeSize and the live
+// filename are read via atomic load since the transfer goroutine mutates
+// them concurrently in write()/resetFile()/submitFile().
+func (ft *FileTransfer) Stats() FileTransferStats {
+	liveName, _ := ft.liveName.Load().(string)
+	files, bytes := diskUsage(ft.dir, ft.prefix, liveName)
+
+	return FileTransferStats{
+		Name:         ft.prefix,
+		WriteSize:    atomic.LoadInt64(&ft.writeSize),
+		RotatedFiles: files,
+		RotatedBytes: bytes,
+		BufferDepth:  len(ft.buffer),
+		BufferCap:    cap(ft.buffer),
+		Dropped:      atomic.LoadInt64(&ft.dropped),
+	}
+}
+
 func (ft *FileTransfer) write(data [][]byte) {
 	if ft.file == nil {
 		if err := ft.resetFile(); err != nil {
@@ -140,12 +178,14 @@ func (ft *FileTransfer) write(data [][]byte) {
 		}
 	}
 
-	length := 0
+	length := int64(0)
 	for _, d := range data {
-		length += len(d) + 1
+		length += int64(len(d)) + 1
 	}
 
-	if TransferFileSize-ft.writeSize < length {
+	writeSize := atomic.LoadInt64(&ft.writeSize)
+
+	if TransferFileSize-writeSize < length {
 		if err := ft.submitFile(); err != nil {
 			logger.Errorf("submit file error: %v", err)
 		}
@@ -155,12 +195,18 @@ func (ft *FileTransfer) write(data [][]byte) {
 
 			return
 		}
+
+		writeSize = 0
 	}
 
 	for _, b := range data {
-		copy(ft.memoryBuffer[ft.writeSize:], b)
-		ft.writeSize += len(b)
-		ft.memoryBuffer[ft.writeSize] = '\n'
-		ft.writeSize++
+		copy(ft.mapping.bytes[writeSize:], b)
+		writeSize += int64(len(b))
+		ft.mapping.bytes[writeSize] = '\n'
+		writeSize++
 	}
+
+	// single store after the copy loop: writeSize only needs to be visible
+	// to Stats() once the whole batch has landed in the mapping.
+	atomic.StoreInt64(&ft.writeSize, writeSize)
 }
@@ -0,0 +1,48 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package logtail
+
+import (
+	"os"
+	"testing"
+)
+
+func TestFileTransferRotatesAcrossSizeThreshold(t *testing.T) {
+	dir := t.TempDir()
+
+	ft := &FileTransfer{dir: dir, prefix: "rotate"}
+	ft.router = &Router{name: "rotate", close: make(chan struct{})}
+
+	if err := ft.resetFile(); err != nil {
+		t.Fatalf("resetFile: %v", err)
+	}
+	defer func() { _ = ft.submitFile() }()
+
+	// fill the mapped file almost to TransferFileSize so the next write
+	// cannot fit and must trigger a rotation.
+	filler := make([]byte, TransferFileSize-10)
+	ft.write([][]byte{filler})
+
+	if ft.writeSize != int64(len(filler)+1) {
+		t.Fatalf("expected writeSize %d after filler write, got %d", len(filler)+1, ft.writeSize)
+	}
+
+	// this chunk no longer fits: write() must submit the current file,
+	// reset to a fresh mapping, and write the chunk into it.
+	chunk := []byte("rotated-onto-new-file")
+	ft.write([][]byte{chunk})
+
+	if ft.writeSize != int64(len(chunk)+1) {
+		t.Fatalf("expected rotation to reset writeSize to %d, got %d", len(chunk)+1, ft.writeSize)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+
+	if len(entries) == 0 {
+		t.Fatalf("expected the rotated-out file to remain on disk")
+	}
+}
@@ -0,0 +1,183 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package logtail
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/vogo/logger"
+	"golang.org/x/sys/unix"
+)
+
+// journalSocketPath is the well-known systemd-journald native protocol socket.
+const journalSocketPath = "/run/systemd/journal/socket"
+
+// defaultJournalPriority is syslog LOG_INFO, used when a router does not configure one.
+const defaultJournalPriority = 6
+
+func init() {
+	RegisterTransferType("journal", "forward lines to the local systemd journal", newJournalTransferFromConfig)
+}
+
+// newJournalTransferFromConfig builds a JournalTransfer from a
+// TransferConfig: Priority is the per-router syslog priority (0 falls back
+// to LOG_INFO) and Fields are the static structured fields appended to
+// every record.
+func newJournalTransferFromConfig(config *TransferConfig) Transfer {
+	return NewJournalTransfer(config.Priority, config.Fields)
+}
+
+// JournalTransfer forwards each line to the local systemd journal over its
+// native datagram protocol, so routers can sink straight into journald
+// without shelling out to logger(1).
+type JournalTransfer struct {
+	router   *Router
+	conn     *net.UnixConn
+	priority int
+	fields   map[string]string
+}
+
+// NewJournalTransfer creates a Transfer writing to /run/systemd/journal/socket.
+// priority is a syslog priority (0-7); 0 falls back to LOG_INFO. fields are
+// static structured fields appended to every record, e.g. {"UNIT": "logtail"}.
+func NewJournalTransfer(priority int, fields map[string]string) Transfer {
+	if priority <= 0 {
+		priority = defaultJournalPriority
+	}
+
+	return &JournalTransfer{
+		priority: priority,
+		fields:   fields,
+	}
+}
+
+func (jt *JournalTransfer) start(r *Router) error {
+	jt.router = r
+
+	addr, err := net.ResolveUnixAddr("unixgram", journalSocketPath)
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, addr)
+	if err != nil {
+		return err
+	}
+
+	jt.conn = conn
+
+	return nil
+}
+
+func (jt *JournalTransfer) Trans(serverID string, data ...[]byte) error {
+	for _, d := range data {
+		if err := jt.send(d); err != nil {
+			logger.Errorf("journal transfer [%s] send error: %+v", serverID, err)
+		}
+	}
+
+	return nil
+}
+
+func (jt *JournalTransfer) send(message []byte) error {
+	payload := jt.buildPayload(message)
+
+	if _, _, err := jt.conn.WriteMsgUnix(payload, nil, nil); err != nil {
+		if !isDatagramTooLarge(err) {
+			return err
+		}
+
+		return jt.sendViaMemfd(payload)
+	}
+
+	return nil
+}
+
+// buildPayload renders the journald native protocol record: one KEY=VALUE
+// line per field, or KEY\n<uint64-le length><bytes>\n for values with newlines.
+func (jt *JournalTransfer) buildPayload(message []byte) []byte {
+	var buf bytes.Buffer
+
+	appendJournalField(&buf, "MESSAGE", message)
+	appendJournalField(&buf, "PRIORITY", []byte(strconv.Itoa(jt.priority)))
+	appendJournalField(&buf, "SYSLOG_IDENTIFIER", []byte(jt.router.name))
+
+	for k, v := range jt.fields {
+		appendJournalField(&buf, strings.ToUpper(k), []byte(v))
+	}
+
+	return buf.Bytes()
+}
+
+func appendJournalField(buf *bytes.Buffer, key string, value []byte) {
+	if !bytes.ContainsRune(value, '\n') {
+		buf.WriteString(key)
+		buf.WriteByte('=')
+		buf.Write(value)
+		buf.WriteByte('\n')
+
+		return
+	}
+
+	buf.WriteString(key)
+	buf.WriteByte('\n')
+
+	length := make([]byte, 8)
+	binary.LittleEndian.PutUint64(length, uint64(len(value)))
+	buf.Write(length)
+	buf.Write(value)
+	buf.WriteByte('\n')
+}
+
+func isDatagramTooLarge(err error) bool {
+	return errors.Is(err, unix.EMSGSIZE) || errors.Is(err, unix.ENOBUFS)
+}
+
+// sendViaMemfd writes the payload to an unlinked, sealed memfd and passes it
+// as an SCM_RIGHTS ancillary message, the fallback journald itself uses once
+// a datagram no longer fits the socket's SO_SNDBUF. journald only accepts
+// memfds sealed against further resizing/writing, so the memfd is created
+// with MFD_ALLOW_SEALING and sealed with F_ADD_SEALS after the payload is
+// written, before the Sendmsg.
+func (jt *JournalTransfer) sendViaMemfd(payload []byte) error {
+	fd, err := unix.MemfdCreate("logtail-journal", unix.MFD_ALLOW_SEALING|unix.MFD_CLOEXEC)
+	if err != nil {
+		return err
+	}
+	defer unix.Close(fd)
+
+	if err := unix.Ftruncate(fd, int64(len(payload))); err != nil {
+		return err
+	}
+
+	mem, err := unix.Mmap(fd, 0, len(payload), unix.PROT_READ|unix.PROT_WRITE, unix.MAP_SHARED)
+	if err != nil {
+		return err
+	}
+
+	copy(mem, payload)
+
+	if err := unix.Munmap(mem); err != nil {
+		return err
+	}
+
+	const sealFlags = unix.F_SEAL_SHRINK | unix.F_SEAL_GROW | unix.F_SEAL_WRITE
+
+	if _, err := unix.FcntlInt(uintptr(fd), unix.F_ADD_SEALS, sealFlags); err != nil {
+		return err
+	}
+
+	file, err := jt.conn.File()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return unix.Sendmsg(int(file.Fd()), nil, unix.UnixRights(fd), nil, 0)
+}
@@ -0,0 +1,80 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package webapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/vogo/logtail"
+)
+
+// routeToContainer handles the /container add/delete routes, dispatched by
+// the top-level request dispatcher the same way routeToTransfer is called
+// for /transfer.
+func routeToContainer(runner *logtail.Runner, request *http.Request, response http.ResponseWriter, router string) {
+	switch router {
+	case OpAdd:
+		addContainerSource(runner, request, response)
+	case OpDelete:
+		deleteContainerSource(runner, request, response)
+	default:
+		routeToNotFound(response)
+	}
+}
+
+func addContainerSource(runner *logtail.Runner, request *http.Request, response http.ResponseWriter) {
+	config := &logtail.ContainerSourceConfig{}
+
+	if err := json.NewDecoder(request.Body).Decode(config); err != nil {
+		routeToError(response, err)
+
+		return
+	}
+
+	if err := runner.AddContainerSource(config); err != nil {
+		routeToError(response, err)
+
+		return
+	}
+
+	routeToSuccess(response)
+}
+
+func deleteContainerSource(runner *logtail.Runner, request *http.Request, response http.ResponseWriter) {
+	config := &logtail.ContainerSourceConfig{}
+
+	if err := json.NewDecoder(request.Body).Decode(config); err != nil {
+		routeToError(response, err)
+
+		return
+	}
+
+	name := config.Container
+	if name == "" {
+		name = config.Label
+	}
+
+	if err := runner.RemoveContainerSource(config.Router, name); err != nil {
+		routeToError(response, err)
+
+		return
+	}
+
+	routeToSuccess(response)
+}
@@ -0,0 +1,76 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package webapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/vogo/logger"
+	"github.com/vogo/logtail"
+)
+
+var errStreamingUnsupported = errors.New("response writer does not support streaming")
+
+// routeToStream upgrades the request to Server-Sent Events and pushes every
+// logtail.Event published by workers and transfers until the client
+// disconnects. Called by the top-level request dispatcher for the /stream
+// route, the same way routeToTransfer is called for /transfer.
+func routeToStream(_ *logtail.Runner, request *http.Request, response http.ResponseWriter) {
+	flusher, ok := response.(http.Flusher)
+	if !ok {
+		routeToError(response, errStreamingUnsupported)
+
+		return
+	}
+
+	response.Header().Set("content-type", "text/event-stream")
+	response.Header().Set("cache-control", "no-cache")
+	response.Header().Set("connection", "keep-alive")
+	response.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	events, unsubscribe := logtail.Events().Subscribe()
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-request.Context().Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+
+			b, err := json.Marshal(event)
+			if err != nil {
+				logger.Errorf("marshal stream event error: %+v", err)
+
+				continue
+			}
+
+			if _, err := fmt.Fprintf(response, "data: %s\n\n", b); err != nil {
+				return
+			}
+
+			flusher.Flush()
+		}
+	}
+}
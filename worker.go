@@ -32,14 +32,40 @@ import (
 var ErrWorkerCommandStopped = errors.New("worker command stopped")
 
 type worker struct {
-	mu      sync.Mutex
-	id      string
-	server  *Server
-	stopper *gstop.Stopper
-	dynamic bool      // command generated dynamically
-	command string    // command lines
-	cmd     *exec.Cmd // command object
-	filters map[string]*Filter
+	mu        sync.Mutex
+	id        string
+	server    *Server
+	stopper   *gstop.Stopper
+	dynamic   bool      // command generated dynamically
+	command   string    // command lines
+	cmd       *exec.Cmd // command object
+	filters   map[string]*Filter
+	startedAt time.Time // set each time start() begins running
+	restarts  int       // count of command restarts after a non-dynamic failure
+}
+
+// WorkerStats is a point-in-time snapshot of a worker's uptime and restart
+// history, reported over /stats.
+type WorkerStats struct {
+	ID       string `json:"id"`
+	Uptime   int64  `json:"uptime_seconds"`
+	Restarts int    `json:"restarts"`
+}
+
+func (w *worker) Stats() WorkerStats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var uptime int64
+	if !w.startedAt.IsZero() {
+		uptime = int64(time.Since(w.startedAt).Seconds())
+	}
+
+	return WorkerStats{
+		ID:       w.id,
+		Uptime:   uptime,
+		Restarts: w.restarts,
+	}
 }
 
 func (w *worker) Write(data []byte) (int, error) {
@@ -47,8 +73,9 @@ func (w *worker) Write(data []byte) (int, error) {
 	newData := make([]byte, len(data))
 	copy(newData, data)
 
-	for _, r := range w.filters {
+	for routerID, r := range w.filters {
 		r.receive(newData)
+		w.publishRouterMatch(routerID)
 	}
 
 	_, _ = w.server.Write(newData)
@@ -56,9 +83,35 @@ func (w *worker) Write(data []byte) (int, error) {
 	return len(newData), nil
 }
 
+func (w *worker) publishEvent(status EventStatus, progress *EventProgress) {
+	Events().Publish(Event{
+		ID:        w.id,
+		ParentID:  w.server.id,
+		Status:    status,
+		Progress:  progress,
+		Timestamp: time.Now().UnixNano(),
+	})
+}
+
+// publishRouterMatch reports that routerID's filter received a chunk written
+// to w. ParentID is w.id, the same worker/router parent-child relationship
+// publishEvent uses for w itself, and ID is the router id rather than its
+// name so an SSE consumer can join it with that router's file-transfer
+// events (both now key off the router id).
+func (w *worker) publishRouterMatch(routerID string) {
+	Events().Publish(Event{
+		ID:        routerID,
+		ParentID:  w.id,
+		Status:    EventRunning,
+		Progress:  &EventProgress{Matches: 1},
+		Timestamp: time.Now().UnixNano(),
+	})
+}
+
 func (w *worker) writeToFilters(bytes []byte) (int, error) {
-	for _, r := range w.filters {
+	for routerID, r := range w.filters {
 		r.receive(bytes)
+		w.publishRouterMatch(routerID)
 	}
 
 	return len(bytes), nil
@@ -72,11 +125,14 @@ func (w *worker) StartRouterFilter(router *Router) {
 	case <-w.stopper.C:
 		return
 	default:
+		routerRegistry.Store(router.id, router.name)
+
 		filter := newFilter(w, router)
 		w.filters[router.id] = filter
 
 		go func() {
 			defer delete(w.filters, router.id)
+			defer routerRegistry.Delete(router.id)
 			filter.start()
 		}()
 	}
@@ -84,9 +140,19 @@ func (w *worker) StartRouterFilter(router *Router) {
 
 // nolint:gosec //ignore this.
 func (w *worker) start() {
+	workerRegistry.Store(w.id, w)
+
 	go func() {
+		w.mu.Lock()
+		w.startedAt = time.Now()
+		w.mu.Unlock()
+
+		w.publishEvent(EventStarted, nil)
+
 		defer func() {
 			w.stop()
+			w.publishEvent(EventCompleted, nil)
+			workerRegistry.Delete(w.id)
 			logger.Infof("worker [%s] stopped", w.id)
 		}()
 
@@ -112,6 +178,7 @@ func (w *worker) start() {
 
 				if err := w.cmd.Run(); err != nil {
 					logger.Errorf("worker [%s] command error: %+v, command: %s", w.id, err, w.command)
+					w.publishEvent(EventError, nil)
 
 					// if the command is generated dynamic, should not restart by self, send error instead.
 					if w.dynamic {
@@ -125,6 +192,11 @@ func (w *worker) start() {
 						return
 					default:
 						logger.Errorf("worker [%s] failed, retry after 10s! command: %s", w.id, w.command)
+
+						w.mu.Lock()
+						w.restarts++
+						w.mu.Unlock()
+
 						time.Sleep(CommandFailRetryInterval)
 					}
 				}
@@ -192,7 +264,29 @@ func startWorker(s *Server, command string, dynamic bool) *worker {
 	return runWorker
 }
 
+// serverRegistry tracks servers by id so code outside the server/router
+// package files (e.g. container sources attaching by server id) can find
+// the Server to attach new dynamic workers to.
+var serverRegistry sync.Map // id -> *Server
+
+// workerRegistry and routerRegistry back /stats: they track the live
+// workers and routers so a scrape can list the routers and report worker
+// uptime and restarts without the server/router package files needing to know about webapi.
+var workerRegistry sync.Map // id -> *worker
+var routerRegistry sync.Map // id -> name (string)
+
+func serverByID(id string) (*Server, bool) {
+	v, ok := serverRegistry.Load(id)
+	if !ok {
+		return nil, false
+	}
+
+	return v.(*Server), true
+}
+
 func newWorker(workerServer *Server, command string, dynamic bool) *worker {
+	serverRegistry.Store(workerServer.id, workerServer)
+
 	workerID := fmt.Sprintf("%s-%d", workerServer.id, len(workerServer.workers))
 	if command == "" {
 		workerID = fmt.Sprintf("%s-default", workerServer.id)